@@ -0,0 +1,15 @@
+package braintree
+
+// Address is a billing or shipping address attached to a payment method
+// or transaction.
+type Address struct {
+	XMLName           string `xml:"address"`
+	FirstName         string `xml:"first-name,omitempty"`
+	LastName          string `xml:"last-name,omitempty"`
+	StreetAddress     string `xml:"street-address,omitempty"`
+	ExtendedAddress   string `xml:"extended-address,omitempty"`
+	Locality          string `xml:"locality,omitempty"`
+	Region            string `xml:"region,omitempty"`
+	PostalCode        string `xml:"postal-code,omitempty"`
+	CountryCodeAlpha2 string `xml:"country-code-alpha2,omitempty"`
+}
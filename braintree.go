@@ -0,0 +1,189 @@
+package braintree
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Braintree is a client for the Braintree gateway API. Gateway types
+// (TransactionGateway, CreditCardGateway, ...) embed *Braintree and call
+// execute to issue requests against config.Environment.
+type Braintree struct {
+	config     Configuration
+	httpClient *http.Client
+	breakers   circuitBreakers
+}
+
+// New returns a Braintree client configured with config, after applying
+// opts (see WithLocale). If config.RetryPolicy was left unset
+// (MaxAttempts == 0), it defaults to DefaultRetryPolicy; pass an explicit
+// RetryPolicy{MaxAttempts: 1} to disable retries instead.
+func New(config Configuration, opts ...func(*Configuration)) *Braintree {
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.RetryPolicy.MaxAttempts == 0 {
+		config.RetryPolicy = DefaultRetryPolicy()
+	}
+	return &Braintree{
+		config:     config,
+		httpClient: http.DefaultClient,
+	}
+}
+
+// Response is the parsed result of a single gateway request.
+type Response struct {
+	StatusCode int
+	Body       []byte
+	Header     http.Header
+}
+
+func (r *Response) transaction() (*Transaction, error) {
+	var v Transaction
+	if err := xml.Unmarshal(r.Body, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *Response) creditCard() (*CreditCard, error) {
+	var v CreditCard
+	if err := xml.Unmarshal(r.Body, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// invalidResponseError wraps a gateway response whose status code the
+// caller did not expect.
+type invalidResponseError struct {
+	response *Response
+}
+
+func (e *invalidResponseError) Error() string {
+	return fmt.Sprintf("braintree: unexpected response status %d: %s", e.response.StatusCode, e.response.Body)
+}
+
+// execute issues method/path against the Braintree gateway, retrying
+// according to g.config.RetryPolicy when the request is idempotent (see
+// retryableRequest) and short-circuiting via a per-host circuit breaker
+// after repeated failures. All waiting between attempts respects
+// ctx.Done(). If g.config.RetryObserver is set, it is called once per
+// attempt with the outcome, so callers can record attempt counts for
+// observability.
+func (g *Braintree) execute(ctx context.Context, method, path string, body interface{}) (*Response, error) {
+	idempotencyKey := idempotencyKeyFromContext(ctx)
+	policy := g.config.RetryPolicy
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	retryable := retryableRequest(method, idempotencyKey)
+	host := g.config.Environment
+	breaker := g.breakers.forHost(host)
+	observer := g.config.RetryObserver
+
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = xml.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if !breaker.allow() {
+			if observer != nil {
+				observer(host, attempt, 0, ErrCircuitOpen)
+			}
+			return nil, ErrCircuitOpen
+		}
+
+		resp, err := g.doRequest(ctx, method, path, payload, idempotencyKey)
+
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		if observer != nil {
+			observer(host, attempt, statusCode, err)
+		}
+
+		if err == nil && !policy.isRetryableStatus(resp.StatusCode) {
+			breaker.recordSuccess()
+			return resp, nil
+		}
+
+		breaker.recordFailure()
+		if err != nil {
+			lastErr = err
+		} else {
+			lastErr = &invalidResponseError{resp}
+		}
+
+		if !retryable || attempt == maxAttempts {
+			break
+		}
+
+		wait := policy.backoff(attempt)
+		if err == nil {
+			if ra := retryAfter(resp.Header); ra > 0 {
+				wait = ra
+			}
+		}
+		if sleepErr := sleepWithContext(ctx, wait); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+
+	return nil, lastErr
+}
+
+// doRequest performs a single HTTP attempt, with no retry logic of its
+// own. It is split out from execute so the retry loop above stays
+// readable.
+func (g *Braintree) doRequest(ctx context.Context, method, path string, payload []byte, idempotencyKey string) (*Response, error) {
+	url := g.config.Environment + "/merchants/" + g.config.Merchant + "/" + path
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(g.config.PublicKey, g.config.PrivateKey)
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Accept", "application/xml")
+	if g.config.Locale != "" {
+		req.Header.Set("Accept-Language", g.config.Locale)
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Body:       respBody,
+		Header:     resp.Header,
+	}, nil
+}
@@ -0,0 +1,29 @@
+package braintree
+
+// Configuration holds the settings used to construct a Braintree client:
+// API credentials and environment, plus cross-cutting behavior such as
+// automatic retries and localized error messages.
+type Configuration struct {
+	Environment string
+	Merchant    string
+	PublicKey   string
+	PrivateKey  string
+
+	// RetryPolicy configures automatic retries of idempotent requests
+	// made by Braintree.execute. Leaving MaxAttempts at 0 makes New
+	// apply DefaultRetryPolicy; set RetryPolicy explicitly (e.g.
+	// RetryPolicy{MaxAttempts: 1}) to disable retries instead.
+	RetryPolicy RetryPolicy
+
+	// RetryObserver, if set, is called by Braintree.execute after every
+	// attempt (including the first), so callers can record attempt
+	// counts and outcomes without reimplementing the retry loop.
+	RetryObserver RetryObserver
+
+	// Locale is sent as the Accept-Language header on every request, so
+	// that validation errors and gateway-rejection/verification-failure
+	// reasons in the XML response come back rendered in the merchant's
+	// language (e.g. "en", "tr", "de-DE"). Left empty, Braintree falls
+	// back to its own default. See WithLocale.
+	Locale string
+}
@@ -0,0 +1,282 @@
+package braintree
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// PaymentState is the lifecycle state of a payment attempt tracked by the
+// ControlTower.
+type PaymentState string
+
+const (
+	PaymentStateInitiated PaymentState = "initiated"
+	PaymentStateInFlight  PaymentState = "in_flight"
+	PaymentStateSucceeded PaymentState = "succeeded"
+	PaymentStateFailed    PaymentState = "failed"
+)
+
+// ErrAlreadyPaid is returned when a call is made with an idempotency key
+// that has already completed successfully. The stored transaction is
+// returned alongside the error so callers can still access the result.
+var ErrAlreadyPaid = errors.New("braintree: payment already completed for this idempotency key")
+
+// ErrPaymentInFlight is returned when a call is made with an idempotency
+// key whose previous attempt has not yet reached a terminal state.
+var ErrPaymentInFlight = errors.New("braintree: payment is already in flight for this idempotency key")
+
+// PaymentAttempt is a single idempotency-key-scoped record of a payment
+// operation (create, submit for settlement, refund, or void).
+type PaymentAttempt struct {
+	IdempotencyKey string
+	Operation      string
+	State          PaymentState
+	TransactionID  string
+	Result         *Transaction
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Store persists PaymentAttempts so that ControlTower can detect duplicate
+// requests and reconcile in-flight attempts after a crash. Implementations
+// are expected to be safe for concurrent use.
+type Store interface {
+	// CreateIfAbsent atomically claims attempt.IdempotencyKey: it inserts
+	// attempt when no record exists yet, or when the existing record is
+	// Failed (eligible for retry, preserving its original CreatedAt).
+	// Otherwise it leaves the store untouched, returns inserted=false,
+	// and hands back the existing record so the caller can choose
+	// between ErrAlreadyPaid and ErrPaymentInFlight. This is the
+	// compare-and-swap primitive ControlTower.run uses to claim a key;
+	// a separate Get followed by Put would let two concurrent callers
+	// with the same key both observe no prior attempt and both submit.
+	CreateIfAbsent(ctx context.Context, attempt *PaymentAttempt) (inserted bool, existing *PaymentAttempt, err error)
+	Put(ctx context.Context, attempt *PaymentAttempt) error
+	ListInFlightOlderThan(ctx context.Context, age time.Duration) ([]*PaymentAttempt, error)
+}
+
+// ControlTower sits in front of TransactionGateway's mutating operations
+// and uses a client-supplied idempotency key to guarantee that a request
+// is never applied to the processor twice, even if the caller's process
+// crashes between issuing the request and persisting its result. Every
+// method also stashes the key on the context it passes down to the
+// gateway, which is what lets Braintree.execute safely retry the
+// underlying POST/PUT on a transient failure: execute only retries
+// mutating requests that carry an idempotency key.
+type ControlTower struct {
+	gateway *TransactionGateway
+	store   Store
+}
+
+// NewControlTower returns a ControlTower that guards gateway with store.
+func NewControlTower(gateway *TransactionGateway, store Store) *ControlTower {
+	return &ControlTower{gateway: gateway, store: store}
+}
+
+// Create initiates a transaction, recording the attempt under key. A
+// repeated call with the same key returns the previously stored result
+// together with ErrAlreadyPaid, or ErrPaymentInFlight, instead of
+// re-submitting the charge.
+func (ct *ControlTower) Create(ctx context.Context, key string, req *TransactionRequest) (*Transaction, error) {
+	ctx = withIdempotencyKey(ctx, key)
+	return ct.run(ctx, key, "create", func() (*Transaction, error) {
+		return ct.gateway.Create(ctx, req)
+	})
+}
+
+// SubmitForSettlement submits the transaction with the given id for
+// settlement, guarded by the idempotency key.
+func (ct *ControlTower) SubmitForSettlement(ctx context.Context, key, id string, amount ...*Decimal) (*Transaction, error) {
+	ctx = withIdempotencyKey(ctx, key)
+	return ct.run(ctx, key, "submit_for_settlement", func() (*Transaction, error) {
+		return ct.gateway.SubmitForSettlement(ctx, id, amount...)
+	})
+}
+
+// Refund refunds the transaction with the given id, guarded by the
+// idempotency key.
+func (ct *ControlTower) Refund(ctx context.Context, key, id string, amount ...*Decimal) (*Transaction, error) {
+	ctx = withIdempotencyKey(ctx, key)
+	return ct.run(ctx, key, "refund", func() (*Transaction, error) {
+		return ct.gateway.Refund(ctx, id, amount...)
+	})
+}
+
+// Void voids the transaction with the given id, guarded by the idempotency
+// key.
+func (ct *ControlTower) Void(ctx context.Context, key, id string) (*Transaction, error) {
+	ctx = withIdempotencyKey(ctx, key)
+	return ct.run(ctx, key, "void", func() (*Transaction, error) {
+		return ct.gateway.Void(ctx, id)
+	})
+}
+
+func (ct *ControlTower) run(ctx context.Context, key, operation string, do func() (*Transaction, error)) (*Transaction, error) {
+	now := time.Now()
+	attempt := &PaymentAttempt{
+		IdempotencyKey: key,
+		Operation:      operation,
+		State:          PaymentStateInitiated,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	inserted, existing, err := ct.store.CreateIfAbsent(ctx, attempt)
+	if err != nil {
+		return nil, err
+	}
+	if !inserted {
+		if existing.State == PaymentStateSucceeded {
+			return existing.Result, ErrAlreadyPaid
+		}
+		return nil, ErrPaymentInFlight
+	}
+
+	// The key is ours: record that the gateway call is actually about to
+	// go out before making it, so a crash between here and do() returning
+	// leaves a durable Store holding InFlight (eligible for Reconcile)
+	// rather than Initiated (which Reconcile does not know how to resolve,
+	// since no request may have reached the gateway yet).
+	attempt.State = PaymentStateInFlight
+	attempt.UpdatedAt = time.Now()
+	if err := ct.store.Put(ctx, attempt); err != nil {
+		return nil, err
+	}
+
+	tx, doErr := do()
+	attempt.UpdatedAt = time.Now()
+	if doErr != nil {
+		attempt.State = PaymentStateFailed
+		if err := ct.store.Put(ctx, attempt); err != nil {
+			return nil, err
+		}
+		return nil, doErr
+	}
+
+	attempt.State = PaymentStateSucceeded
+	attempt.Result = tx
+	if tx != nil {
+		attempt.TransactionID = tx.Id
+	}
+	if err := ct.store.Put(ctx, attempt); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Reconcile finds payment attempts that have been InFlight for longer than
+// olderThan and resolves them against the gateway's authoritative state by
+// calling TransactionGateway.Find. This is meant to run periodically (e.g.
+// from a cron job) to recover from a process crash between submitting a
+// request and persisting its outcome.
+func (ct *ControlTower) Reconcile(ctx context.Context, olderThan time.Duration) error {
+	stale, err := ct.store.ListInFlightOlderThan(ctx, olderThan)
+	if err != nil {
+		return err
+	}
+	for _, attempt := range stale {
+		if attempt.TransactionID == "" {
+			// We crashed before the gateway ever returned an id; there is
+			// nothing to look up, so leave it Failed for manual review.
+			attempt.State = PaymentStateFailed
+			attempt.UpdatedAt = time.Now()
+			if err := ct.store.Put(ctx, attempt); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tx, findErr := ct.gateway.Find(ctx, attempt.TransactionID)
+		attempt.UpdatedAt = time.Now()
+		switch {
+		case findErr != nil:
+			attempt.State = PaymentStateFailed
+		case isFailedTransactionStatus(tx.Status):
+			attempt.State = PaymentStateFailed
+			attempt.Result = tx
+		default:
+			attempt.State = PaymentStateSucceeded
+			attempt.Result = tx
+		}
+		if err := ct.store.Put(ctx, attempt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isFailedTransactionStatus reports whether status is a terminal,
+// non-charged outcome at the gateway, as opposed to one where money
+// actually moved (or is in flight to move). Reconcile uses this so a
+// stale InFlight attempt that the gateway declined or voided is recorded
+// as Failed rather than Succeeded - recording it as Succeeded would make
+// ControlTower.run return ErrAlreadyPaid and permanently block a
+// legitimate retry of a charge that never went through.
+func isFailedTransactionStatus(status TransactionStatus) bool {
+	switch status {
+	case TransactionStatusProcessorDeclined,
+		TransactionStatusGatewayRejected,
+		TransactionStatusVoided,
+		TransactionStatusFailed,
+		TransactionStatusSettlementDeclined:
+		return true
+	}
+	return false
+}
+
+// MemoryStore is an in-memory reference implementation of Store, suitable
+// for tests but not for production: it loses every attempt on the very
+// crash ControlTower exists to recover from. See FileStore for a durable
+// single-process alternative, or back ControlTower with Postgres or bbolt
+// once multi-process access matters.
+type MemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]*PaymentAttempt
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{attempts: make(map[string]*PaymentAttempt)}
+}
+
+func (s *MemoryStore) CreateIfAbsent(ctx context.Context, attempt *PaymentAttempt) (bool, *PaymentAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.attempts[attempt.IdempotencyKey]
+	if ok && existing.State != PaymentStateFailed {
+		copied := *existing
+		return false, &copied, nil
+	}
+
+	claimed := *attempt
+	if ok {
+		claimed.CreatedAt = existing.CreatedAt
+	}
+	s.attempts[attempt.IdempotencyKey] = &claimed
+	return true, nil, nil
+}
+
+func (s *MemoryStore) Put(ctx context.Context, attempt *PaymentAttempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *attempt
+	s.attempts[attempt.IdempotencyKey] = &copied
+	return nil
+}
+
+func (s *MemoryStore) ListInFlightOlderThan(ctx context.Context, age time.Duration) ([]*PaymentAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cutoff := time.Now().Add(-age)
+	var stale []*PaymentAttempt
+	for _, attempt := range s.attempts {
+		if attempt.State == PaymentStateInFlight && attempt.UpdatedAt.Before(cutoff) {
+			copied := *attempt
+			stale = append(stale, &copied)
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,131 @@
+package braintree
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreCreateIfAbsentBlocksConcurrentClaim(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+
+	inserted, _, err := s.CreateIfAbsent(ctx, &PaymentAttempt{
+		IdempotencyKey: "key-1",
+		State:          PaymentStateInitiated,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	})
+	if err != nil || !inserted {
+		t.Fatalf("first claim: inserted=%v err=%v, want true, nil", inserted, err)
+	}
+
+	inserted, existing, err := s.CreateIfAbsent(ctx, &PaymentAttempt{
+		IdempotencyKey: "key-1",
+		State:          PaymentStateInitiated,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("second claim: unexpected error %v", err)
+	}
+	if inserted {
+		t.Fatal("second claim of an in-flight key was inserted, want rejected")
+	}
+	if existing == nil || existing.State != PaymentStateInitiated {
+		t.Fatalf("second claim existing = %+v, want State Initiated", existing)
+	}
+
+	failed := &PaymentAttempt{IdempotencyKey: "key-1", State: PaymentStateFailed, UpdatedAt: time.Now()}
+	if err := s.Put(ctx, failed); err != nil {
+		t.Fatalf("Put failed attempt: %v", err)
+	}
+
+	inserted, _, err = s.CreateIfAbsent(ctx, &PaymentAttempt{
+		IdempotencyKey: "key-1",
+		State:          PaymentStateInitiated,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	})
+	if err != nil || !inserted {
+		t.Fatalf("reclaim after failure: inserted=%v err=%v, want true, nil", inserted, err)
+	}
+}
+
+func TestFileStorePersistsAcrossRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attempts.json")
+	ctx := context.Background()
+
+	s1, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	succeeded := &PaymentAttempt{
+		IdempotencyKey: "key-1",
+		State:          PaymentStateSucceeded,
+		TransactionID:  "tx_1",
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if err := s1.Put(ctx, succeeded); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// Simulate a process restart: a fresh FileStore pointed at the same
+	// path must see the attempt that was durably recorded before the
+	// "crash", which is the entire reason FileStore exists over
+	// MemoryStore.
+	s2, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore (reload): %v", err)
+	}
+	inserted, existing, err := s2.CreateIfAbsent(ctx, &PaymentAttempt{
+		IdempotencyKey: "key-1",
+		State:          PaymentStateInitiated,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("CreateIfAbsent after reload: %v", err)
+	}
+	if inserted {
+		t.Fatal("reloaded FileStore did not see the persisted attempt")
+	}
+	if existing == nil || existing.TransactionID != "tx_1" {
+		t.Fatalf("reloaded attempt = %+v, want TransactionID tx_1", existing)
+	}
+}
+
+func TestControlTowerCreateIsIdempotent(t *testing.T) {
+	var creates int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&creates, 1)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`<transaction><id>tx_1</id><status>authorized</status></transaction>`))
+	}))
+	defer srv.Close()
+
+	gw := &TransactionGateway{Braintree: New(Configuration{Environment: srv.URL, Merchant: "m"})}
+	ct := NewControlTower(gw, NewMemoryStore())
+	ctx := context.Background()
+
+	tx1, err := ct.Create(ctx, "key-1", &TransactionRequest{})
+	if err != nil {
+		t.Fatalf("first Create: %v", err)
+	}
+
+	tx2, err := ct.Create(ctx, "key-1", &TransactionRequest{})
+	if err != ErrAlreadyPaid {
+		t.Fatalf("repeated Create err = %v, want ErrAlreadyPaid", err)
+	}
+	if tx2 == nil || tx2.Id != tx1.Id {
+		t.Fatalf("repeated Create returned %+v, want the original result %+v", tx2, tx1)
+	}
+	if got := atomic.LoadInt32(&creates); got != 1 {
+		t.Fatalf("gateway saw %d create requests, want exactly 1", got)
+	}
+}
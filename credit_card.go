@@ -0,0 +1,27 @@
+package braintree
+
+// CreditCard is a card stored in the Braintree vault.
+type CreditCard struct {
+	XMLName        string   `xml:"credit-card"`
+	Token          string   `xml:"token,omitempty"`
+	CardholderName string   `xml:"cardholder-name,omitempty"`
+	BillingAddress *Address `xml:"billing-address,omitempty"`
+	Default        bool     `xml:"default,omitempty"`
+	ExpirationDate string   `xml:"expiration-date,omitempty"`
+}
+
+// SearchResult is a page of ids returned by an "expiring ids"-style
+// lookup, paired with the page size used to paginate over them.
+type SearchResult struct {
+	PageSize int
+	IDs      []string
+}
+
+// CreditCardSearchResult is one page of a CreditCardGateway search.
+type CreditCardSearchResult struct {
+	TotalItems        int
+	TotalIDs          []string
+	CurrentPageNumber int
+	PageSize          int
+	CreditCards       []*CreditCard
+}
@@ -24,6 +24,38 @@ func (g *CreditCardGateway) Create(ctx context.Context, card *CreditCard) (*Cred
 	return nil, &invalidResponseError{resp}
 }
 
+// CreditCardCloneRequest describes the overrides to apply when cloning a
+// stored payment method with CreditCardGateway.Clone.
+type CreditCardCloneRequest struct {
+	XMLName        string                  `xml:"credit-card"`
+	CardholderName string                  `xml:"cardholder-name,omitempty"`
+	BillingAddress *Address                `xml:"billing-address,omitempty"`
+	Options        *CreditCardCloneOptions `xml:"options,omitempty"`
+}
+
+// CreditCardCloneOptions controls how the cloned payment method is vaulted.
+type CreditCardCloneOptions struct {
+	MakeDefault bool `xml:"make-default,omitempty"`
+	VerifyCard  bool `xml:"verify-card,omitempty"`
+}
+
+// Clone duplicates the stored payment method identified by token into a
+// new vault entry, optionally overriding the cardholder name, billing
+// address, and default/verification options. This is useful for migrating
+// a card between vault customers, or making a scratch copy to verify
+// without mutating the original token.
+func (g *CreditCardGateway) Clone(ctx context.Context, token string, req *CreditCardCloneRequest) (*CreditCard, error) {
+	resp, err := g.execute(ctx, "POST", "payment_methods/"+token+"/clone", req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case 201:
+		return resp.creditCard()
+	}
+	return nil, &invalidResponseError{resp}
+}
+
 // Update updates a credit card.
 func (g *CreditCardGateway) Update(ctx context.Context, card *CreditCard) (*CreditCard, error) {
 	resp, err := g.execute(ctx, "PUT", "payment_methods/"+card.Token, card)
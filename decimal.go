@@ -0,0 +1,47 @@
+package braintree
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Decimal is a fixed-point monetary amount: Unscaled units at 10^-Scale,
+// e.g. Unscaled=1999, Scale=2 is "19.99". Amounts are never represented
+// as float64 so that retrying or refunding a charge can't drift a cent
+// from rounding.
+type Decimal struct {
+	Unscaled int64
+	Scale    int
+}
+
+// NewDecimal returns a Decimal of unscaled units at scale digits of
+// precision.
+func NewDecimal(unscaled int64, scale int) *Decimal {
+	return &Decimal{Unscaled: unscaled, Scale: scale}
+}
+
+// String renders the amount as a plain decimal string, e.g. "19.99".
+func (d *Decimal) String() string {
+	if d == nil {
+		return ""
+	}
+	if d.Scale <= 0 {
+		return strconv.FormatInt(d.Unscaled, 10)
+	}
+	sign := ""
+	unscaled := d.Unscaled
+	if unscaled < 0 {
+		sign = "-"
+		unscaled = -unscaled
+	}
+	s := strconv.FormatInt(unscaled, 10)
+	for len(s) <= d.Scale {
+		s = "0" + s
+	}
+	whole, frac := s[:len(s)-d.Scale], s[len(s)-d.Scale:]
+	return fmt.Sprintf("%s%s.%s", sign, whole, frac)
+}
+
+func (d *Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
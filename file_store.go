@@ -0,0 +1,138 @@
+package braintree
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileStore is a durable, single-process Store implementation that
+// persists every PaymentAttempt to a JSON file on disk, so that
+// ControlTower's crash-recovery guarantee actually holds across a process
+// restart - a MemoryStore loses every attempt on exactly the crash it
+// exists to survive. It is a reference implementation suited to
+// low-volume, single-instance deployments; once write throughput or
+// multi-process access matters, back ControlTower with Postgres or bbolt
+// instead.
+type FileStore struct {
+	mu       sync.Mutex
+	path     string
+	attempts map[string]*PaymentAttempt
+}
+
+// NewFileStore loads the PaymentAttempts persisted at path, or starts
+// empty if path does not exist yet.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, attempts: make(map[string]*PaymentAttempt)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return s, nil
+	}
+	if err := json.Unmarshal(data, &s.attempts); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileStore) CreateIfAbsent(ctx context.Context, attempt *PaymentAttempt) (bool, *PaymentAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prior, ok := s.attempts[attempt.IdempotencyKey]
+	if ok && prior.State != PaymentStateFailed {
+		copied := *prior
+		return false, &copied, nil
+	}
+
+	claimed := *attempt
+	if ok {
+		claimed.CreatedAt = prior.CreatedAt
+	}
+	s.attempts[attempt.IdempotencyKey] = &claimed
+	if err := s.persistLocked(); err != nil {
+		if ok {
+			s.attempts[attempt.IdempotencyKey] = prior
+		} else {
+			delete(s.attempts, attempt.IdempotencyKey)
+		}
+		return false, nil, err
+	}
+	return true, nil, nil
+}
+
+func (s *FileStore) Put(ctx context.Context, attempt *PaymentAttempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prior := s.attempts[attempt.IdempotencyKey]
+	copied := *attempt
+	s.attempts[attempt.IdempotencyKey] = &copied
+	if err := s.persistLocked(); err != nil {
+		if prior != nil {
+			s.attempts[attempt.IdempotencyKey] = prior
+		} else {
+			delete(s.attempts, attempt.IdempotencyKey)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) ListInFlightOlderThan(ctx context.Context, age time.Duration) ([]*PaymentAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-age)
+	var stale []*PaymentAttempt
+	for _, attempt := range s.attempts {
+		if attempt.State == PaymentStateInFlight && attempt.UpdatedAt.Before(cutoff) {
+			copied := *attempt
+			stale = append(stale, &copied)
+		}
+	}
+	return stale, nil
+}
+
+// persistLocked atomically rewrites s.path with the current in-memory
+// state: write to a temp file in the same directory, then rename it over
+// the target, so a crash mid-write never leaves a truncated or corrupt
+// file for the next NewFileStore to choke on. Callers must hold s.mu.
+func (s *FileStore) persistLocked() error {
+	data, err := json.Marshal(s.attempts)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil {
+		os.Remove(tmpPath)
+		return writeErr
+	}
+	if closeErr != nil {
+		os.Remove(tmpPath)
+		return closeErr
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package braintree
+
+// WithLocale returns a New option that sets Configuration.Locale to
+// locale (e.g. "en", "tr", "de-DE"). When set, Braintree.execute sends it
+// as the Accept-Language header on every request, so that validation
+// errors in the XML "errors" block - and the gateway-rejection and
+// verification-failure reasons surfaced by TransactionGateway.Create and
+// CreditCardGateway.Create - come back rendered in the merchant's
+// language instead of English.
+func WithLocale(locale string) func(*Configuration) {
+	return func(c *Configuration) {
+		c.Locale = locale
+	}
+}
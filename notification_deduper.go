@@ -0,0 +1,203 @@
+package braintree
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// TransactionChangeEvent describes a meaningful change to a transaction,
+// as detected by NotificationDeduper. Downstream ledger/accounting code
+// can consume ChangedFields directly instead of recomputing a diff
+// between Previous and Current itself. Previous is nil the first time an
+// id is seen.
+type TransactionChangeEvent struct {
+	TransactionID string
+	Previous      *TransactionSnapshot
+	Current       *Transaction
+	ChangedFields []string
+}
+
+// DedupeStore persists the last snapshot recorded for each transaction
+// id, so NotificationDeduper can both detect a no-op update and produce
+// an exact field-level diff against the prior state. Implementations
+// must be safe for concurrent use.
+type DedupeStore interface {
+	Get(ctx context.Context, transactionID string) (snapshot TransactionSnapshot, ok bool, err error)
+	Put(ctx context.Context, transactionID string, snapshot TransactionSnapshot) error
+}
+
+// NotificationDeduper wraps webhook processing (and search-based polling)
+// so a Transaction update is only surfaced when one of the fields that
+// matter to downstream consumers actually changed, rather than on every
+// notification or poll.
+type NotificationDeduper struct {
+	store DedupeStore
+}
+
+// NewNotificationDeduper returns a NotificationDeduper backed by store.
+func NewNotificationDeduper(store DedupeStore) *NotificationDeduper {
+	return &NotificationDeduper{store: store}
+}
+
+// Process compares tx against the snapshot last recorded for its id and
+// returns a TransactionChangeEvent if, and only if, a tracked field
+// changed. It returns a nil event (and nil error) for a no-op update.
+func (d *NotificationDeduper) Process(ctx context.Context, tx *Transaction) (*TransactionChangeEvent, error) {
+	current := newTransactionSnapshot(tx)
+
+	previous, hadPrevious, err := d.store.Get(ctx, tx.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := diffSnapshots(previous, current, hadPrevious)
+	if hadPrevious && len(changed) == 0 {
+		return nil, nil
+	}
+
+	if err := d.store.Put(ctx, tx.Id, current); err != nil {
+		return nil, err
+	}
+
+	event := &TransactionChangeEvent{
+		TransactionID: tx.Id,
+		Current:       tx,
+		ChangedFields: changed,
+	}
+	if hadPrevious {
+		p := previous
+		event.Previous = &p
+	}
+	return event, nil
+}
+
+// TransactionSnapshot is the subset of a Transaction that
+// NotificationDeduper tracks for change detection: status, settlement,
+// disbursement, and any linked refunds/disputes/escrow movement.
+type TransactionSnapshot struct {
+	Status              TransactionStatus
+	Amount              string
+	SettlementBatchId   string
+	DisbursementDetails string
+	RefundIds           []string
+	DisputeIds          []string
+	EscrowStatus        EscrowStatus
+}
+
+func newTransactionSnapshot(tx *Transaction) TransactionSnapshot {
+	amount := ""
+	if tx.Amount != nil {
+		amount = tx.Amount.String()
+	}
+
+	disbursement := ""
+	if d := tx.DisbursementDetails; d != nil {
+		settlementAmount := ""
+		if d.SettlementAmount != nil {
+			settlementAmount = d.SettlementAmount.String()
+		}
+		disbursement = d.DisbursementDate + "|" + settlementAmount
+	}
+
+	refundIds := append([]string(nil), tx.RefundIds...)
+	sort.Strings(refundIds)
+	disputeIds := append([]string(nil), tx.DisputeIds...)
+	sort.Strings(disputeIds)
+
+	return TransactionSnapshot{
+		Status:              tx.Status,
+		Amount:              amount,
+		SettlementBatchId:   tx.SettlementBatchId,
+		DisbursementDetails: disbursement,
+		RefundIds:           refundIds,
+		DisputeIds:          disputeIds,
+		EscrowStatus:        tx.EscrowStatus,
+	}
+}
+
+// fields lists every tracked field name, in a stable order, for use when
+// an id is seen for the first time and there is no prior snapshot to
+// diff against.
+func (s TransactionSnapshot) fields() []string {
+	return []string{
+		"Status",
+		"Amount",
+		"SettlementBatchId",
+		"DisbursementDetails",
+		"RefundIds",
+		"DisputeIds",
+		"EscrowStatus",
+	}
+}
+
+// diffSnapshots reports which tracked fields differ between previous and
+// current. If hadPrevious is false there is nothing to diff against, so
+// every field is reported (consistent with "this is new to us").
+func diffSnapshots(previous, current TransactionSnapshot, hadPrevious bool) []string {
+	if !hadPrevious {
+		return current.fields()
+	}
+
+	var changed []string
+	if previous.Status != current.Status {
+		changed = append(changed, "Status")
+	}
+	if previous.Amount != current.Amount {
+		changed = append(changed, "Amount")
+	}
+	if previous.SettlementBatchId != current.SettlementBatchId {
+		changed = append(changed, "SettlementBatchId")
+	}
+	if previous.DisbursementDetails != current.DisbursementDetails {
+		changed = append(changed, "DisbursementDetails")
+	}
+	if !stringsEqual(previous.RefundIds, current.RefundIds) {
+		changed = append(changed, "RefundIds")
+	}
+	if !stringsEqual(previous.DisputeIds, current.DisputeIds) {
+		changed = append(changed, "DisputeIds")
+	}
+	if previous.EscrowStatus != current.EscrowStatus {
+		changed = append(changed, "EscrowStatus")
+	}
+	return changed
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MemoryDedupeStore is an in-memory reference implementation of
+// DedupeStore, suitable for tests and single-process deployments.
+type MemoryDedupeStore struct {
+	mu        sync.Mutex
+	snapshots map[string]TransactionSnapshot
+}
+
+// NewMemoryDedupeStore returns an empty MemoryDedupeStore.
+func NewMemoryDedupeStore() *MemoryDedupeStore {
+	return &MemoryDedupeStore{snapshots: make(map[string]TransactionSnapshot)}
+}
+
+func (s *MemoryDedupeStore) Get(ctx context.Context, transactionID string) (TransactionSnapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot, ok := s.snapshots[transactionID]
+	return snapshot, ok, nil
+}
+
+func (s *MemoryDedupeStore) Put(ctx context.Context, transactionID string, snapshot TransactionSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots[transactionID] = snapshot
+	return nil
+}
@@ -0,0 +1,263 @@
+package braintree
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy configures automatic retries of idempotent requests made by
+// Braintree.execute: GET requests, and POST/PUT requests that carry a
+// client-supplied idempotency key (see ControlTower). It lives on
+// Configuration so it can be tuned per-environment without touching call
+// sites.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// A value of 1 disables retries. Left at 0, New replaces the whole
+	// policy with DefaultRetryPolicy.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the computed backoff regardless of Multiplier. The
+	// cap is applied before Jitter, so a jittered delay can exceed it by
+	// up to the Jitter fraction. A Retry-After header on the response
+	// replaces the computed backoff and is not capped.
+	MaxBackoff time.Duration
+
+	// Multiplier is applied to the backoff after every attempt.
+	Multiplier float64
+
+	// Jitter is the fraction (0-1) of the computed backoff that is
+	// randomized to avoid thundering-herd retries across clients.
+	Jitter float64
+
+	// RetryableStatuses lists HTTP status codes that should be retried in
+	// addition to transient network errors. 429 and 5xx are retried by
+	// default even if this is left empty.
+	RetryableStatuses []int
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used when Configuration.RetryPolicy
+// is left unset.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+func (p RetryPolicy) isRetryableStatus(status int) bool {
+	if status == 429 || status >= 500 {
+		return true
+	}
+	for _, s := range p.RetryableStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= p.Multiplier
+	}
+	if max := float64(p.MaxBackoff); max > 0 && d > max {
+		d = max
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (rand.Float64()*2 - 1)
+	}
+	if d < 0 {
+		d = 0
+	}
+	return time.Duration(d)
+}
+
+// ErrCircuitOpen is returned by execute when the circuit breaker for a
+// host is open, i.e. enough consecutive failures have been observed
+// recently that further requests are short-circuited to avoid piling on
+// during a Braintree outage.
+var ErrCircuitOpen = errors.New("braintree: circuit breaker open, short-circuiting request")
+
+// RetryObserver is invoked after every attempt, successful or not, so
+// callers can record attempt counts and outcomes for observability.
+type RetryObserver func(host string, attempt int, statusCode int, err error)
+
+// breakerState is the state of a circuitBreaker.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker is a consecutive-failure breaker, one instance per host.
+// After threshold consecutive failures it opens for cooldown; once
+// cooldown elapses it lets exactly one "half-open" probe request through
+// before deciding whether to close (probe succeeded) or re-open (probe
+// failed) - it never lets the full request volume resume in one step.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	state     breakerState
+	openUntil time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Now().Before(b.openUntil) {
+			return false
+		}
+		// Cooldown elapsed: let a single probe through with a clean
+		// failure count, rather than resetting straight to closed and
+		// letting every caller pile back in at once.
+		b.state = breakerHalfOpen
+		b.failures = 0
+		return true
+	case breakerHalfOpen:
+		// A probe is already outstanding; hold everyone else back until
+		// it resolves.
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.state = breakerClosed
+	b.openUntil = time.Time{}
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == breakerHalfOpen || (b.threshold > 0 && b.failures >= b.threshold) {
+		b.state = breakerOpen
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+// circuitBreakerThreshold and circuitBreakerCooldown control how many
+// consecutive failures open the per-host circuit, and how long it stays
+// open. They are package-level defaults rather than Configuration fields
+// because they protect Braintree's infrastructure, not a single
+// merchant's retry budget.
+const (
+	circuitBreakerThreshold = 5
+	circuitBreakerCooldown  = 30 * time.Second
+)
+
+// circuitBreakers holds one breaker per host, created lazily.
+type circuitBreakers struct {
+	mu     sync.Mutex
+	byHost map[string]*circuitBreaker
+}
+
+func (c *circuitBreakers) forHost(host string) *circuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byHost == nil {
+		c.byHost = make(map[string]*circuitBreaker)
+	}
+	b, ok := c.byHost[host]
+	if !ok {
+		b = newCircuitBreaker(circuitBreakerThreshold, circuitBreakerCooldown)
+		c.byHost[host] = b
+	}
+	return b
+}
+
+// retryableRequest reports whether method may be safely retried. GET is
+// always safe; POST/PUT are only safe when idempotencyKey is non-empty,
+// since that is what lets Braintree (and our own ControlTower) recognize
+// and collapse a re-sent request.
+func retryableRequest(method, idempotencyKey string) bool {
+	if method == "GET" {
+		return true
+	}
+	if (method == "POST" || method == "PUT") && idempotencyKey != "" {
+		return true
+	}
+	return false
+}
+
+// sleepWithContext waits for d, returning early with ctx.Err() if ctx is
+// done first.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// retryAfter parses a 429 response's Retry-After header, which per RFC
+// 7231 is either a number of seconds or an HTTP date. It returns 0 if the
+// header is absent, unparseable, or already in the past, in which case
+// the caller falls back to RetryPolicy's own backoff computation.
+func retryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(v); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// idempotencyKeyCtxKey is the context key under which ControlTower
+// stashes the caller-supplied idempotency key, so execute can tell a
+// mutating request is safe to retry without threading the key through
+// every gateway method signature.
+type idempotencyKeyCtxKey struct{}
+
+// withIdempotencyKey returns a context carrying key for execute's retry
+// policy to pick up.
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+// idempotencyKeyFromContext returns the idempotency key stashed by
+// withIdempotencyKey, or "" if none was set.
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyCtxKey{}).(string)
+	return key
+}
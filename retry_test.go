@@ -0,0 +1,117 @@
+package braintree
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterThresholdAndHalfOpens(t *testing.T) {
+	b := newCircuitBreaker(2, 20*time.Millisecond)
+
+	if !b.allow() {
+		t.Fatal("closed breaker should allow")
+	}
+	b.recordFailure()
+	if !b.allow() {
+		t.Fatal("below threshold, breaker should still allow")
+	}
+	b.recordFailure() // 2nd consecutive failure hits the threshold
+	if b.allow() {
+		t.Fatal("breaker should be open after hitting the failure threshold")
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("breaker should let a single probe through once cooldown elapses")
+	}
+	if b.allow() {
+		t.Fatal("breaker should block a second caller while a half-open probe is outstanding")
+	}
+
+	b.recordFailure() // probe failed
+	if b.allow() {
+		t.Fatal("breaker should re-open after a failed half-open probe")
+	}
+}
+
+func TestCircuitBreakerClosesOnSuccessfulProbe(t *testing.T) {
+	b := newCircuitBreaker(1, 10*time.Millisecond)
+	b.recordFailure() // opens immediately: threshold is 1
+	if b.allow() {
+		t.Fatal("breaker should be open")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("breaker should let a probe through")
+	}
+	b.recordSuccess()
+	if !b.allow() {
+		t.Fatal("breaker should be closed and allow requests after a successful probe")
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     500 * time.Millisecond,
+		Multiplier:     2,
+	}
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 500 * time.Millisecond}, // capped by MaxBackoff
+	}
+	for _, c := range cases {
+		if got := p.backoff(c.attempt); got != c.want {
+			t.Errorf("backoff(%d) = %v, want %v", c.attempt, got, c.want)
+		}
+	}
+}
+
+func TestExecuteInvokesRetryObserverPerAttempt(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`<transaction><id>tx_1</id></transaction>`))
+	}))
+	defer srv.Close()
+
+	var observed []string
+	cfg := Configuration{
+		Environment: srv.URL,
+		Merchant:    "m",
+		RetryPolicy: RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+		RetryObserver: func(host string, attempt int, statusCode int, err error) {
+			observed = append(observed, fmt.Sprintf("%d:%d:%v", attempt, statusCode, err))
+		},
+	}
+	gw := &TransactionGateway{Braintree: New(cfg)}
+	ctx := withIdempotencyKey(context.Background(), "key-1")
+
+	tx, err := gw.Create(ctx, &TransactionRequest{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if tx.Id != "tx_1" {
+		t.Fatalf("tx.Id = %q, want tx_1", tx.Id)
+	}
+
+	want := []string{"1:500:<nil>", "2:201:<nil>"}
+	if !stringsEqual(observed, want) {
+		t.Fatalf("observed attempts = %v, want %v", observed, want)
+	}
+}
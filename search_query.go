@@ -0,0 +1,62 @@
+package braintree
+
+// MultiField is a search field that matches any of Items, e.g. a set of
+// transaction ids or statuses.
+type MultiField struct {
+	Name  string
+	Items []string
+}
+
+// SearchQuery builds an advanced_search request body for TransactionGateway
+// and CreditCardGateway.
+type SearchQuery struct {
+	MultiFields []*MultiField
+
+	// Reverse walks results newest-first instead of oldest-first when
+	// used with TransactionGateway.SearchIterator.
+	Reverse bool
+}
+
+// AddMultiField adds (or returns the existing) MultiField named name.
+func (q *SearchQuery) AddMultiField(name string) *MultiField {
+	for _, f := range q.MultiFields {
+		if f.Name == name {
+			return f
+		}
+	}
+	f := &MultiField{Name: name}
+	q.MultiFields = append(q.MultiFields, f)
+	return f
+}
+
+// shallowCopy returns a copy of q safe to mutate (e.g. to swap out the
+// "ids" field for a specific page) without affecting the original query.
+func (q *SearchQuery) shallowCopy() *SearchQuery {
+	copied := *q
+	copied.MultiFields = make([]*MultiField, len(q.MultiFields))
+	for i, f := range q.MultiFields {
+		field := *f
+		copied.MultiFields[i] = &field
+	}
+	return &copied
+}
+
+// searchResults is the advanced_search_ids response: every matching id,
+// plus the page size to slice them into pages of.
+type searchResults struct {
+	PageSize int `xml:"page-size"`
+	IDs      struct {
+		Item []string `xml:"item"`
+	} `xml:"ids"`
+}
+
+// TransactionSearchResult is one page of a TransactionGateway search.
+type TransactionSearchResult struct {
+	TotalItems        int
+	TotalIDs          []string
+	CurrentPageNumber int
+	PageSize          int
+	Transactions      []*Transaction
+
+	searchQuery *SearchQuery
+}
@@ -0,0 +1,28 @@
+package braintree
+
+import "context"
+
+// TestingGateway exposes sandbox-only operations. It is only available
+// against Braintree's sandbox environment.
+type TestingGateway struct {
+	*Braintree
+}
+
+// Testing returns a TestingGateway for sandbox-only operations.
+func (g *Braintree) Testing() TestingGateway {
+	return TestingGateway{g}
+}
+
+// Settle marks the transaction with the given id as settled. This action
+// is only available in the sandbox environment.
+func (g TestingGateway) Settle(ctx context.Context, id string) (*Transaction, error) {
+	resp, err := g.execute(ctx, "PUT", "transactions/"+id+"/settle", nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case 200:
+		return resp.transaction()
+	}
+	return nil, &invalidResponseError{resp}
+}
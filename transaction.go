@@ -0,0 +1,47 @@
+package braintree
+
+// EscrowStatus reports where a marketplace transaction is in the escrow
+// hold/release lifecycle.
+type EscrowStatus string
+
+// DisbursementDetails describes when, and for how much, a settled
+// transaction was paid out to the merchant.
+type DisbursementDetails struct {
+	DisbursementDate string   `xml:"disbursement-date,omitempty"`
+	SettlementAmount *Decimal `xml:"settlement-amount,omitempty"`
+}
+
+// Transaction is a Braintree transaction: an authorization, and
+// everything that happened to it afterward (settlement, refunds,
+// disputes, 3D Secure authentication, ...).
+type Transaction struct {
+	XMLName             string               `xml:"transaction"`
+	Id                  string               `xml:"id,omitempty"`
+	Status              TransactionStatus    `xml:"status,omitempty"`
+	Amount              *Decimal             `xml:"amount,omitempty"`
+	SettlementBatchId   string               `xml:"settlement-batch-id,omitempty"`
+	DisbursementDetails *DisbursementDetails `xml:"disbursement-details,omitempty"`
+	RefundIds           []string             `xml:"refund-ids>item,omitempty"`
+	DisputeIds          []string             `xml:"dispute-ids>item,omitempty"`
+	EscrowStatus        EscrowStatus         `xml:"escrow-status,omitempty"`
+	ThreeDSecureInfo    *ThreeDSecureInfo    `xml:"three-d-secure-info,omitempty"`
+}
+
+// TransactionRequest builds a request to TransactionGateway.Create (and,
+// for the Amount field, SubmitForSettlement/Refund).
+type TransactionRequest struct {
+	XMLName string   `xml:"transaction"`
+	Amount  *Decimal `xml:"amount,omitempty"`
+
+	// ThreeDSecureAuthenticationID is the verification id returned by
+	// TransactionGateway.Init3DS/Complete3DS. Setting it tells Create to
+	// attach that prior authentication's liability-shift result to the
+	// authorization instead of running 3D Secure inline.
+	ThreeDSecureAuthenticationID string `xml:"three-d-secure-authentication-id,omitempty"`
+}
+
+// TransactionCloneRequest builds a request to TransactionGateway.Clone.
+type TransactionCloneRequest struct {
+	XMLName string   `xml:"transaction-clone-request"`
+	Amount  *Decimal `xml:"amount,omitempty"`
+}
@@ -0,0 +1,173 @@
+package braintree
+
+import "context"
+
+// iteratorBatchSize is the number of transactions fetched per underlying
+// advanced_search call while streaming through SearchIterator.
+const iteratorBatchSize = 100
+
+// Cursor identifies a position within a transaction search so that
+// iteration can be resumed later, e.g. from a nightly reconciliation job
+// that persists its progress between runs.
+type Cursor struct {
+	// LastID is the id of the last transaction returned before the
+	// cursor was captured. Empty means "start from the beginning".
+	LastID string
+
+	// Reverse walks the result set newest-first when true, oldest-first
+	// when false. It must match the Reverse setting the query was
+	// created with.
+	Reverse bool
+}
+
+// TransactionIterator streams transaction bodies matching a search query
+// in bounded batches instead of hydrating every match up front. Note that
+// Braintree's advanced_search_ids endpoint has no server-side paging of
+// its own - it always returns the complete matching id list in one
+// response, so that part of a search is unavoidably O(matching ids) in
+// memory either way. What SearchIterator actually saves, compared to
+// Search/SearchNext, is never hydrating more than iteratorBatchSize full
+// *Transaction bodies at a time; for merchants with millions of matches,
+// the id list (bare strings) is orders of magnitude cheaper to hold than
+// the transactions themselves.
+type TransactionIterator struct {
+	gateway *TransactionGateway
+	query   *SearchQuery
+	reverse bool
+
+	ids    []string
+	cursor Cursor
+
+	batch []*Transaction
+	err   error
+}
+
+// SearchIterator returns an iterator over transactions matching query,
+// starting from the beginning (or the end, if query.Reverse is set).
+func (g *TransactionGateway) SearchIterator(ctx context.Context, query *SearchQuery) *TransactionIterator {
+	return g.SearchIteratorFrom(ctx, query, Cursor{Reverse: query.Reverse})
+}
+
+// SearchIteratorFrom resumes iteration over transactions matching query
+// after cursor, e.g. after a process restart. Oldest-first, it starts
+// immediately after cursor.LastID. Newest-first (cursor.Reverse), it
+// first returns every transaction newer than cursor.LastID again,
+// including those already returned before the cursor was captured, and
+// then continues with the ones older than it.
+func (g *TransactionGateway) SearchIteratorFrom(ctx context.Context, query *SearchQuery, cursor Cursor) *TransactionIterator {
+	return &TransactionIterator{
+		gateway: g,
+		query:   query,
+		reverse: cursor.Reverse,
+		cursor:  cursor,
+	}
+}
+
+// Next advances the iterator and returns the next transaction, fetching
+// another batch of ids/transactions from the gateway as needed. It
+// returns (nil, nil) once the result set is exhausted.
+func (it *TransactionIterator) Next(ctx context.Context) (*Transaction, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+
+	for len(it.batch) == 0 {
+		if err := it.fetchNextBatch(ctx); err != nil {
+			it.err = err
+			return nil, err
+		}
+		if len(it.ids) == 0 && len(it.batch) == 0 {
+			return nil, nil
+		}
+	}
+
+	tx := it.batch[0]
+	it.batch = it.batch[1:]
+	it.cursor = Cursor{LastID: tx.Id, Reverse: it.reverse}
+	return tx, nil
+}
+
+// Cursor returns a resumption point as of the last transaction returned
+// by Next. Callers should persist it after processing each transaction
+// (or each batch) so a crashed process can resume with SearchIteratorFrom
+// instead of reprocessing from the start. In reverse mode the resumed
+// iterator still returns the transactions newer than the cursor again
+// (see SearchIteratorFrom).
+func (it *TransactionIterator) Cursor() Cursor {
+	return it.cursor
+}
+
+func (it *TransactionIterator) fetchNextBatch(ctx context.Context) error {
+	if it.ids == nil {
+		ids, err := it.fetchIDs(ctx)
+		if err != nil {
+			return err
+		}
+		it.ids = ids
+	}
+	if len(it.ids) == 0 {
+		return nil
+	}
+
+	end := iteratorBatchSize
+	if end > len(it.ids) {
+		end = len(it.ids)
+	}
+	page := it.ids[:end]
+	it.ids = it.ids[end:]
+
+	pageQuery := it.query.shallowCopy()
+	pageQuery.AddMultiField("ids").Items = page
+	transactions, err := it.gateway.fetchTransactions(ctx, pageQuery)
+	if err != nil {
+		return err
+	}
+	it.batch = transactions
+	return nil
+}
+
+func (it *TransactionIterator) fetchIDs(ctx context.Context) ([]string, error) {
+	searchResult, err := it.gateway.fetchTransactionIDs(ctx, it.query)
+	if err != nil {
+		return nil, err
+	}
+	ids := searchResult.IDs.Item
+
+	if it.reverse {
+		for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+	}
+
+	if it.cursor.LastID == "" {
+		return ids, nil
+	}
+
+	for i, id := range ids {
+		if id != it.cursor.LastID {
+			continue
+		}
+		if !it.reverse {
+			// Oldest-first: everything after the cursor, including
+			// transactions created since the last run (which sort after
+			// it), is exactly what comes next.
+			return ids[i+1:], nil
+		}
+		// Newest-first: ids[:i] are every transaction newer than the
+		// cursor - both those already returned before the cursor was
+		// captured and any that arrived (or became findable) since.
+		// Surface all of them again first so a resumed reconciliation
+		// run doesn't permanently miss transactions that arrived
+		// between runs, then continue the historical sweep where it
+		// left off. Transactions returned before the cursor are
+		// therefore returned a second time.
+		resumed := make([]string, 0, len(ids)-1)
+		resumed = append(resumed, ids[:i]...)
+		resumed = append(resumed, ids[i+1:]...)
+		return resumed, nil
+	}
+	// LastID is no longer in the result set (e.g. it aged out of the
+	// query's date range); fall back to the full, freshly sorted list
+	// rather than silently returning nothing.
+	return ids, nil
+}
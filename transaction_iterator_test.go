@@ -0,0 +1,73 @@
+package braintree
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func idsServer(ids string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`<search-results><page-size>100</page-size><ids>` + ids + `</ids></search-results>`))
+	}))
+}
+
+func newTestIterator(t *testing.T, srv *httptest.Server, cursor Cursor) *TransactionIterator {
+	t.Helper()
+	gw := &TransactionGateway{Braintree: New(Configuration{Environment: srv.URL, Merchant: "m"})}
+	return gw.SearchIteratorFrom(context.Background(), &SearchQuery{Reverse: cursor.Reverse}, cursor)
+}
+
+func TestFetchIDsForwardResume(t *testing.T) {
+	srv := idsServer(`<item>1</item><item>2</item><item>3</item><item>4</item><item>5</item>`)
+	defer srv.Close()
+
+	it := newTestIterator(t, srv, Cursor{LastID: "2"})
+	ids, err := it.fetchIDs(context.Background())
+	if err != nil {
+		t.Fatalf("fetchIDs: %v", err)
+	}
+	want := []string{"3", "4", "5"}
+	if !stringsEqual(ids, want) {
+		t.Fatalf("fetchIDs = %v, want %v", ids, want)
+	}
+}
+
+func TestFetchIDsReverseResumeSurfacesNewerFirst(t *testing.T) {
+	srv := idsServer(`<item>1</item><item>2</item><item>3</item><item>4</item><item>5</item>`)
+	defer srv.Close()
+
+	// Server always returns ids oldest-first; reverse mode flips that to
+	// 5,4,3,2,1 before applying the cursor. Resuming after "3" (index 2
+	// in the reversed list) must surface 5,4 (newer than the cursor, so
+	// either returned before it or arrived since) before continuing the historical
+	// sweep with 2,1 - not just ids[3:], which would permanently skip
+	// anything newer than the cursor.
+	it := newTestIterator(t, srv, Cursor{LastID: "3", Reverse: true})
+	ids, err := it.fetchIDs(context.Background())
+	if err != nil {
+		t.Fatalf("fetchIDs: %v", err)
+	}
+	want := []string{"5", "4", "2", "1"}
+	if !stringsEqual(ids, want) {
+		t.Fatalf("fetchIDs = %v, want %v", ids, want)
+	}
+}
+
+func TestFetchIDsCursorNotFoundFallsBackToFullList(t *testing.T) {
+	srv := idsServer(`<item>1</item><item>2</item><item>3</item>`)
+	defer srv.Close()
+
+	it := newTestIterator(t, srv, Cursor{LastID: "does-not-exist"})
+	ids, err := it.fetchIDs(context.Background())
+	if err != nil {
+		t.Fatalf("fetchIDs: %v", err)
+	}
+	want := []string{"1", "2", "3"}
+	if !stringsEqual(ids, want) {
+		t.Fatalf("fetchIDs = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,19 @@
+package braintree
+
+// TransactionStatus is the lifecycle status Braintree assigns to a
+// transaction as it moves through authorization, settlement, and any
+// later refund/dispute activity.
+type TransactionStatus string
+
+const (
+	TransactionStatusAuthorizing            TransactionStatus = "authorizing"
+	TransactionStatusAuthorized             TransactionStatus = "authorized"
+	TransactionStatusSubmittedForSettlement TransactionStatus = "submitted_for_settlement"
+	TransactionStatusSettling               TransactionStatus = "settling"
+	TransactionStatusSettled                TransactionStatus = "settled"
+	TransactionStatusSettlementDeclined     TransactionStatus = "settlement_declined"
+	TransactionStatusVoided                 TransactionStatus = "voided"
+	TransactionStatusProcessorDeclined      TransactionStatus = "processor_declined"
+	TransactionStatusGatewayRejected        TransactionStatus = "gateway_rejected"
+	TransactionStatusFailed                 TransactionStatus = "failed"
+)
@@ -0,0 +1,101 @@
+package braintree
+
+import (
+	"context"
+	"encoding/xml"
+)
+
+// EnrolledStatus is the issuer's 3D Secure enrollment status for a card,
+// as reported by the directory server.
+type EnrolledStatus string
+
+// AuthenticationStatus is the outcome of the issuer's authentication
+// challenge (or frictionless flow) for a 3D Secure verification.
+type AuthenticationStatus string
+
+// LiabilityShifted reports whether fraud liability actually moved from
+// the merchant to the card issuer for the transaction.
+type LiabilityShifted string
+
+// LiabilityShiftPossible reports whether a liability shift was available
+// for the card/transaction, regardless of whether it occurred.
+type LiabilityShiftPossible string
+
+// CAVV is the base64-encoded Cardholder Authentication Verification
+// Value returned by the issuer, used by the card networks to prove the
+// authentication took place.
+type CAVV string
+
+// DSTransactionID is the directory server transaction id assigned to the
+// 3D Secure verification.
+type DSTransactionID string
+
+// ECIFlag is the Electronic Commerce Indicator returned by the card
+// network, describing how the transaction was authenticated.
+type ECIFlag string
+
+// ThreeDSecureInfo captures the liability-shift fields Braintree returns
+// on a transaction that went through 3D Secure, so callers can make
+// routing decisions without parsing the raw XML themselves.
+type ThreeDSecureInfo struct {
+	Enrolled               EnrolledStatus         `xml:"enrolled"`
+	Status                 AuthenticationStatus   `xml:"status"`
+	LiabilityShifted       LiabilityShifted       `xml:"liability-shifted"`
+	LiabilityShiftPossible LiabilityShiftPossible `xml:"liability-shift-possible"`
+	CAVV                   CAVV                   `xml:"cavv"`
+	DSTransactionID        DSTransactionID        `xml:"ds-transaction-id"`
+	ECIFlag                ECIFlag                `xml:"eci-flag"`
+}
+
+// ThreeDSInitRequest starts a 3D Secure 2 verification for a card ahead
+// of authorizing a transaction.
+type ThreeDSInitRequest struct {
+	XMLName            string      `xml:"three-d-secure-verification"`
+	Amount             *Decimal    `xml:"amount"`
+	PaymentMethodNonce string      `xml:"payment-method-nonce,omitempty"`
+	CreditCard         *CreditCard `xml:"credit-card,omitempty"`
+}
+
+// ThreeDSInitResponse is returned by Init3DS. HTML contains the
+// redirect/iframe payload the caller's front end must render to run the
+// issuer challenge; ThreeDSVerificationID identifies the verification
+// server-side and is passed to Complete3DS once the challenge finishes.
+type ThreeDSInitResponse struct {
+	ThreeDSVerificationID string `xml:"id"`
+	HTML                  string `xml:"html"`
+}
+
+// Init3DS starts a 3D Secure 2 verification for req, returning the
+// redirect payload to present to the cardholder and a verification id to
+// finalize later with Complete3DS.
+func (g *TransactionGateway) Init3DS(ctx context.Context, req *ThreeDSInitRequest) (*ThreeDSInitResponse, error) {
+	resp, err := g.execute(ctx, "POST", "three_d_secure/verifications", req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case 201:
+		var v ThreeDSInitResponse
+		if err := xml.Unmarshal(resp.Body, &v); err != nil {
+			return nil, err
+		}
+		return &v, nil
+	}
+	return nil, &invalidResponseError{resp}
+}
+
+// Complete3DS finalizes a verification previously started with Init3DS,
+// once the issuer's challenge has completed. The caller then passes
+// verificationID as TransactionRequest.ThreeDSecureAuthenticationID to
+// Create so the authentication result is attached to the authorization.
+func (g *TransactionGateway) Complete3DS(ctx context.Context, verificationID string) (*Transaction, error) {
+	resp, err := g.execute(ctx, "GET", "three_d_secure/verifications/"+verificationID, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case 200:
+		return resp.transaction()
+	}
+	return nil, &invalidResponseError{resp}
+}